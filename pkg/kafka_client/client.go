@@ -3,12 +3,22 @@ package kafka_client
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
+	"crypto/x509"
 	"fmt"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/aws_msk_iam_v2"
 	"github.com/segmentio/kafka-go/sasl/plain"
 	"github.com/segmentio/kafka-go/sasl/scram"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"log"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,26 +32,76 @@ const errorLogLevel = "error"
 const dialerTimeout = 10 * time.Second
 
 type Options struct {
-	BootstrapServers   string `json:"bootstrapServers"`
-	SecurityProtocol   string `json:"securityProtocol"`
-	SaslMechanisms     string `json:"saslMechanisms"`
-	SaslUsername       string `json:"saslUsername"`
-	SaslPassword       string `json:"saslPassword"`
-	HealthcheckTimeout int32  `json:"healthcheckTimeout"`
-	LogLevel           string `json:"logLevel"`
+	BootstrapServers       string `json:"bootstrapServers"`
+	SecurityProtocol       string `json:"securityProtocol"`
+	SaslMechanisms         string `json:"saslMechanisms"`
+	SaslUsername           string `json:"saslUsername"`
+	SaslPassword           string `json:"saslPassword"`
+	HealthcheckTimeout     int32  `json:"healthcheckTimeout"`
+	LogLevel               string `json:"logLevel"`
+	SslCaCert              string `json:"sslCaCert"`
+	SslClientCert          string `json:"sslClientCert"`
+	SslClientKey           string `json:"sslClientKey"`
+	SslInsecureSkipVerify  bool   `json:"sslInsecureSkipVerify"`
+	SslServerName          string `json:"sslServerName"`
+	AwsRegion              string `json:"awsRegion"`
+	AwsAccessKeyId         string `json:"awsAccessKeyId"`
+	AwsSecretAccessKey     string `json:"awsSecretAccessKey"`
+	AwsSessionToken        string `json:"awsSessionToken"`
+	AwsRoleArn             string `json:"awsRoleArn"`
+	DecoderFormat          string `json:"decoderFormat"`
+	SchemaRegistryUrl      string `json:"schemaRegistryUrl"`
+	SchemaRegistryUsername string `json:"schemaRegistryUsername"`
+	SchemaRegistryPassword string `json:"schemaRegistryPassword"`
+	SchemaRegistryTLS      bool   `json:"schemaRegistryTls"`
+	ProtobufDescriptorSet  string `json:"protobufDescriptorSet"`
+	ProtobufMessageType    string `json:"protobufMessageType"`
+	MaxRetries             int32  `json:"maxRetries"`
+	InitialBackoffMs       int32  `json:"initialBackoffMs"`
+	MaxBackoffMs           int32  `json:"maxBackoffMs"`
 }
 
 type KafkaClient struct {
-	Dialer             *kafka.Dialer
-	Reader             *kafka.Reader
-	BootstrapServers   string
-	TimestampMode      string
-	SecurityProtocol   string
-	SaslMechanisms     string
-	SaslUsername       string
-	SaslPassword       string
-	LogLevel           string
-	HealthcheckTimeout int32
+	Dialer                 *kafka.Dialer
+	Reader                 *kafka.Reader
+	BootstrapServers       string
+	TimestampMode          string
+	SecurityProtocol       string
+	SaslMechanisms         string
+	SaslUsername           string
+	SaslPassword           string
+	LogLevel               string
+	HealthcheckTimeout     int32
+	SslCaCert              string
+	SslClientCert          string
+	SslClientKey           string
+	SslInsecureSkipVerify  bool
+	SslServerName          string
+	AwsRegion              string
+	AwsAccessKeyId         string
+	AwsSecretAccessKey     string
+	AwsSessionToken        string
+	AwsRoleArn             string
+	DecoderFormat          string
+	SchemaRegistryUrl      string
+	SchemaRegistryUsername string
+	SchemaRegistryPassword string
+	SchemaRegistryTLS      bool
+	ProtobufDescriptorSet  string
+	ProtobufMessageType    string
+	Decoder                Decoder
+	tracer                 trace.Tracer
+	metrics                *kafkaMetrics
+	MaxRetries             int32
+	InitialBackoffMs       int32
+	MaxBackoffMs           int32
+
+	readerMode            string
+	readerTopic           string
+	readerPartition       int
+	readerAutoOffsetReset string
+	readerGroupID         string
+	readerCommitInterval  time.Duration
 }
 
 type KafkaMessage struct {
@@ -50,20 +110,62 @@ type KafkaMessage struct {
 	Offset    int64
 }
 
-func NewKafkaClient(options Options) KafkaClient {
+func NewKafkaClient(options Options, opts ...ClientOption) KafkaClient {
 	client := KafkaClient{
-		BootstrapServers:   options.BootstrapServers,
-		SecurityProtocol:   options.SecurityProtocol,
-		SaslMechanisms:     options.SaslMechanisms,
-		SaslUsername:       options.SaslUsername,
-		SaslPassword:       options.SaslPassword,
-		LogLevel:           options.LogLevel,
-		HealthcheckTimeout: options.HealthcheckTimeout,
+		BootstrapServers:       options.BootstrapServers,
+		SecurityProtocol:       options.SecurityProtocol,
+		SaslMechanisms:         options.SaslMechanisms,
+		SaslUsername:           options.SaslUsername,
+		SaslPassword:           options.SaslPassword,
+		LogLevel:               options.LogLevel,
+		HealthcheckTimeout:     options.HealthcheckTimeout,
+		SslCaCert:              options.SslCaCert,
+		SslClientCert:          options.SslClientCert,
+		SslClientKey:           options.SslClientKey,
+		SslInsecureSkipVerify:  options.SslInsecureSkipVerify,
+		SslServerName:          options.SslServerName,
+		AwsRegion:              options.AwsRegion,
+		AwsAccessKeyId:         options.AwsAccessKeyId,
+		AwsSecretAccessKey:     options.AwsSecretAccessKey,
+		AwsSessionToken:        options.AwsSessionToken,
+		AwsRoleArn:             options.AwsRoleArn,
+		DecoderFormat:          options.DecoderFormat,
+		SchemaRegistryUrl:      options.SchemaRegistryUrl,
+		SchemaRegistryUsername: options.SchemaRegistryUsername,
+		SchemaRegistryPassword: options.SchemaRegistryPassword,
+		SchemaRegistryTLS:      options.SchemaRegistryTLS,
+		ProtobufDescriptorSet:  options.ProtobufDescriptorSet,
+		ProtobufMessageType:    options.ProtobufMessageType,
+		MaxRetries:             options.MaxRetries,
+		InitialBackoffMs:       options.InitialBackoffMs,
+		MaxBackoffMs:           options.MaxBackoffMs,
 	}
+
+	for _, opt := range opts {
+		opt(&client)
+	}
+	if client.metrics == nil {
+		client.metrics = newKafkaMetrics(nil)
+	}
+
 	return client
 }
 
-func (client *KafkaClient) NewConnection() error {
+// NewConnection builds the client's Dialer and Decoder, retrying failures
+// that isRetryableError classifies as transient with a bounded exponential
+// backoff. Building them opens no broker connection; AWS credentials for
+// AWS_MSK_IAM are not fetched here.
+func (client *KafkaClient) NewConnection() (err error) {
+	_, span := client.startSpan(context.Background(), "kafka_client.NewConnection")
+	defer func() { endSpan(span, err) }()
+
+	maxRetries, initialBackoff, maxBackoff := client.retryConfig()
+
+	return retryWithBackoff(context.Background(), maxRetries, initialBackoff, maxBackoff, client.connect)
+}
+
+// connect performs the one-shot work retried by NewConnection.
+func (client *KafkaClient) connect() error {
 	var err error
 	var mechanism sasl.Mechanism
 
@@ -82,17 +184,83 @@ func (client *KafkaClient) NewConnection() error {
 		dialer.SASLMechanism = mechanism
 	}
 
-	if client.SecurityProtocol == "SASL_SSL" {
-		dialer.TLS = &tls.Config{
-			MinVersion: tls.VersionTLS13,
+	if client.SecurityProtocol == "SASL_SSL" || client.SecurityProtocol == "SSL" {
+		tlsConfig, err := getTLSConfig(client)
+		if err != nil {
+			return fmt.Errorf("unable to build tls config: %w", err)
 		}
+		dialer.TLS = tlsConfig
 	}
 
 	client.Dialer = dialer
 
+	decoder, err := NewDecoder(Options{
+		DecoderFormat:          client.DecoderFormat,
+		SchemaRegistryUrl:      client.SchemaRegistryUrl,
+		SchemaRegistryUsername: client.SchemaRegistryUsername,
+		SchemaRegistryPassword: client.SchemaRegistryPassword,
+		SchemaRegistryTLS:      client.SchemaRegistryTLS,
+		ProtobufDescriptorSet:  client.ProtobufDescriptorSet,
+		ProtobufMessageType:    client.ProtobufMessageType,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to build message decoder: %w", err)
+	}
+	client.Decoder = decoder
+
 	return nil
 }
 
+// getTLSConfig builds a tls.Config from the client's CA/client certificate
+// settings, on top of the hardcoded minimum TLS version. The CA certificate
+// may be given either as a filesystem path or as an inline PEM block.
+func getTLSConfig(client *KafkaClient) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS13,
+		InsecureSkipVerify: client.SslInsecureSkipVerify,
+		ServerName:         client.SslServerName,
+	}
+
+	if client.SslCaCert != "" {
+		caCertPool, err := loadCertPool(client.SslCaCert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load CA certificate: %w", err)
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	if client.SslClientCert != "" || client.SslClientKey != "" {
+		clientCert, err := tls.LoadX509KeyPair(client.SslClientCert, client.SslClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate/key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// loadCertPool reads a CA certificate, either from a PEM-encoded file on
+// disk or directly from an inline PEM string, and returns a pool usable as
+// tls.Config.RootCAs.
+func loadCertPool(caCert string) (*x509.CertPool, error) {
+	pem := []byte(caCert)
+	if !strings.HasPrefix(strings.TrimSpace(caCert), "-----BEGIN") {
+		var err error
+		pem, err = os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA certificate file: %w", err)
+		}
+	}
+
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("unable to parse CA certificate")
+	}
+
+	return caCertPool, nil
+}
+
 func (client *KafkaClient) newReader(topic string, partition int) *kafka.Reader {
 	logger, errorLogger := getKafkaLogger(client.LogLevel)
 
@@ -114,8 +282,20 @@ func (client *KafkaClient) TopicAssign(
 	partition int32,
 	autoOffsetReset string,
 	timestampMode string,
-) error {
+) (err error) {
+	_, span := client.startSpan(context.Background(), "kafka_client.TopicAssign")
+	span.SetAttributes(
+		attribute.String("messaging.destination", topic),
+		attribute.Int("messaging.kafka.partition", int(partition)),
+		attribute.String("kafka_datasource.auto_offset_reset", autoOffsetReset),
+	)
+	defer func() { endSpan(span, err) }()
+
 	client.TimestampMode = timestampMode
+	client.readerMode = "assign"
+	client.readerTopic = topic
+	client.readerPartition = int(partition)
+	client.readerAutoOffsetReset = autoOffsetReset
 
 	var offset int64
 	var high, low int64
@@ -153,16 +333,130 @@ func (client *KafkaClient) TopicAssign(
 	return nil
 }
 
-func (client *KafkaClient) ConsumerPull(ctx context.Context) (KafkaMessage, error) {
-	var message KafkaMessage
+// TopicSubscribe joins topic as part of a Kafka consumer group instead of
+// assigning a single fixed partition: kafka-go load-balances partitions
+// across readers sharing groupID and periodically auto-commits progress, so
+// panels resume from their last committed offset across dashboard reloads.
+// autoOffsetReset only applies the first time a group has no committed
+// offset for a partition.
+func (client *KafkaClient) TopicSubscribe(
+	topic string,
+	groupID string,
+	autoOffsetReset string,
+	timestampMode string,
+	commitInterval time.Duration,
+) error {
+	client.TimestampMode = timestampMode
+	client.readerMode = "subscribe"
+	client.readerTopic = topic
+	client.readerGroupID = groupID
+	client.readerAutoOffsetReset = autoOffsetReset
+	client.readerCommitInterval = commitInterval
+
+	var startOffset int64
+	switch autoOffsetReset {
+	case "earliest":
+		startOffset = kafka.FirstOffset
+	default:
+		startOffset = kafka.LastOffset
+	}
+
+	logger, errorLogger := getKafkaLogger(client.LogLevel)
+
+	client.Reader = kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        strings.Split(client.BootstrapServers, ","),
+		Topic:          topic,
+		GroupID:        groupID,
+		StartOffset:    startOffset,
+		CommitInterval: commitInterval,
+		Dialer:         client.Dialer,
+		Logger:         logger,
+		ErrorLogger:    errorLogger,
+	})
+
+	return nil
+}
+
+// rebuildReader closes the current Reader, if any, and recreates it so
+// ConsumerPull can recover from an unrecoverable read error without the
+// caller re-issuing the original query. In assign mode it resumes from the
+// offset the old Reader had reached, rather than re-applying
+// autoOffsetReset, so a reconnect doesn't rewind or skip messages; in
+// subscribe mode the broker-side committed offset already anchors
+// progress, so rejoining the group is enough.
+func (client *KafkaClient) rebuildReader() error {
+	resumeOffset := int64(-1)
+	if client.Reader != nil {
+		resumeOffset = client.Reader.Offset()
+		client.Reader.Close()
+	}
+
+	if client.readerMode == "subscribe" {
+		return client.TopicSubscribe(
+			client.readerTopic,
+			client.readerGroupID,
+			client.readerAutoOffsetReset,
+			client.TimestampMode,
+			client.readerCommitInterval,
+		)
+	}
+
+	if resumeOffset >= 0 {
+		client.Reader = client.newReader(client.readerTopic, client.readerPartition)
+		return client.Reader.SetOffset(resumeOffset)
+	}
+
+	return client.TopicAssign(
+		client.readerTopic,
+		int32(client.readerPartition),
+		client.readerAutoOffsetReset,
+		client.TimestampMode,
+	)
+}
 
-	msg, err := client.Reader.ReadMessage(ctx)
+func (client *KafkaClient) ConsumerPull(ctx context.Context) (message KafkaMessage, err error) {
+	ctx, span := client.startSpan(ctx, "kafka_client.ConsumerPull")
+	defer func() { endSpan(span, err) }()
+
+	maxRetries, initialBackoff, maxBackoff := client.retryConfig()
+
+	var msg kafka.Message
+	err = retryWithBackoff(ctx, maxRetries, initialBackoff, maxBackoff, func() error {
+		var readErr error
+		msg, readErr = client.Reader.ReadMessage(ctx)
+		if readErr != nil && isRetryableError(readErr) {
+			// The Reader's own internal retries are exhausted; rebuild it so
+			// the next attempt starts from a fresh connection/rebalance.
+			if rebuildErr := client.rebuildReader(); rebuildErr != nil {
+				return fmt.Errorf("unable to rebuild kafka reader: %w", rebuildErr)
+			}
+		}
+		return readErr
+	})
 	if err != nil {
 		return message, fmt.Errorf("error reading message from Kafka: %w", err)
 	}
 
-	if err := json.Unmarshal(msg.Value, &message.Value); err != nil {
-		return message, fmt.Errorf("error unmarshalling message: %w", err)
+	span.SetAttributes(
+		attribute.String("messaging.destination", msg.Topic),
+		attribute.Int("messaging.kafka.partition", msg.Partition),
+	)
+
+	decoder := client.Decoder
+	if decoder == nil {
+		decoder = &JSONDecoder{}
+	}
+
+	message.Value, err = decoder.Decode(msg.Topic, msg)
+	if err != nil {
+		if client.metrics != nil {
+			client.metrics.decodeErrors.WithLabelValues(msg.Topic).Inc()
+		}
+		return message, fmt.Errorf("error decoding message: %w", err)
+	}
+
+	if client.metrics != nil {
+		client.metrics.messagesConsumed.WithLabelValues(msg.Topic, strconv.Itoa(msg.Partition)).Inc()
 	}
 
 	message.Offset = msg.Offset
@@ -171,12 +465,19 @@ func (client *KafkaClient) ConsumerPull(ctx context.Context) (KafkaMessage, erro
 	return message, nil
 }
 
-func (client *KafkaClient) HealthCheck() error {
-	if err := client.NewConnection(); err != nil {
+func (client *KafkaClient) HealthCheck() (err error) {
+	_, span := client.startSpan(context.Background(), "kafka_client.HealthCheck")
+	defer func() { endSpan(span, err) }()
+
+	if client.metrics != nil {
+		start := time.Now()
+		defer func() { client.metrics.healthCheckLatency.Observe(time.Since(start).Seconds()) }()
+	}
+
+	if err = client.NewConnection(); err != nil {
 		return fmt.Errorf("unable to initialize Kafka client: %w", err)
 	}
 	var conn *kafka.Conn
-	var err error
 
 	// It is better to try several times due to possible network issues
 	timeout := time.After(time.Duration(client.HealthcheckTimeout) * time.Millisecond)
@@ -188,7 +489,11 @@ func (client *KafkaClient) HealthCheck() error {
 		case <-timeout:
 			return fmt.Errorf("health check timed out after %d ms: %w", client.HealthcheckTimeout, err)
 		case <-ticker.C:
+			dialStart := time.Now()
 			conn, err = client.Dialer.Dial(network, client.BootstrapServers)
+			if client.metrics != nil {
+				client.metrics.dialDuration.Observe(time.Since(dialStart).Seconds())
+			}
 			if err == nil {
 				defer conn.Close()
 				if _, err = conn.ReadPartitions(); err != nil {
@@ -217,6 +522,8 @@ func getSASLMechanism(client *KafkaClient) (sasl.Mechanism, error) {
 		return scram.Mechanism(scram.SHA256, client.SaslUsername, client.SaslPassword)
 	case "SCRAM-SHA-512":
 		return scram.Mechanism(scram.SHA512, client.SaslUsername, client.SaslPassword)
+	case "AWS_MSK_IAM":
+		return getAWSMSKIAMMechanism(client)
 	case "":
 		return nil, nil
 	default:
@@ -224,29 +531,69 @@ func getSASLMechanism(client *KafkaClient) (sasl.Mechanism, error) {
 	}
 }
 
-func (client *KafkaClient) IsTopicExists(ctx context.Context, topicName string) (bool, error) {
-	var mechanism sasl.Mechanism
-	var err error
+// getAWSMSKIAMMechanism builds a SASL mechanism that signs Kafka connections
+// with AWS SigV4, as required by Amazon MSK IAM authentication. Explicit
+// access key/secret/session token credentials are used when provided,
+// otherwise the default AWS credentials chain is used; AwsRoleArn, if set,
+// is assumed on top of the resolved credentials.
+func getAWSMSKIAMMechanism(client *KafkaClient) (sasl.Mechanism, error) {
+	ctx := context.Background()
+
+	var optFns []func(*config.LoadOptions) error
+	if client.AwsRegion != "" {
+		optFns = append(optFns, config.WithRegion(client.AwsRegion))
+	}
+	if client.AwsAccessKeyId != "" && client.AwsSecretAccessKey != "" {
+		optFns = append(optFns, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			client.AwsAccessKeyId, client.AwsSecretAccessKey, client.AwsSessionToken,
+		)))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS config: %w", err)
+	}
+
+	if client.AwsRoleArn != "" {
+		cfg.Credentials = aws.NewCredentialsCache(
+			stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), client.AwsRoleArn),
+		)
+	}
 
-	conn := kafka.Client{
+	return aws_msk_iam_v2.NewMechanism(cfg), nil
+}
+
+// newAdminClient builds a kafka.Client configured with this connection's
+// SASL mechanism, for one-off administrative requests (metadata, offsets)
+// that don't go through a Dialer or Reader.
+func (client *KafkaClient) newAdminClient() (*kafka.Client, error) {
+	adminClient := &kafka.Client{
 		Addr:    kafka.TCP(strings.Split(client.BootstrapServers, ",")...),
 		Timeout: dialerTimeout,
 	}
 
 	if client.SaslMechanisms != "" {
-		mechanism, err = getSASLMechanism(client)
+		mechanism, err := getSASLMechanism(client)
 		if err != nil {
-			return false, fmt.Errorf("unable to get sasl mechanism: %w", err)
+			return nil, fmt.Errorf("unable to get sasl mechanism: %w", err)
 		}
+		adminClient.Transport = &kafka.Transport{SASL: mechanism}
 	}
 
-	if mechanism != nil {
-		conn.Transport = &kafka.Transport{
-			SASL: mechanism,
-		}
+	return adminClient, nil
+}
+
+func (client *KafkaClient) IsTopicExists(ctx context.Context, topicName string) (exists bool, err error) {
+	ctx, span := client.startSpan(ctx, "kafka_client.IsTopicExists")
+	span.SetAttributes(attribute.String("messaging.destination", topicName))
+	defer func() { endSpan(span, err) }()
+
+	adminClient, err := client.newAdminClient()
+	if err != nil {
+		return false, err
 	}
 
-	meta, err := conn.Metadata(ctx, &kafka.MetadataRequest{})
+	meta, err := adminClient.Metadata(ctx, &kafka.MetadataRequest{})
 	if err != nil {
 		return false, fmt.Errorf("unable to get metadata: %w", err)
 	}
@@ -262,6 +609,87 @@ func (client *KafkaClient) IsTopicExists(ctx context.Context, topicName string)
 	return topicExists, nil
 }
 
+// GetConsumerLag returns the total lag, summed across all partitions, of
+// consumer group groupID on topic: the high watermark minus the group's
+// committed offset for each partition.
+func (client *KafkaClient) GetConsumerLag(ctx context.Context, topic string, groupID string) (int64, error) {
+	adminClient, err := client.newAdminClient()
+	if err != nil {
+		return 0, err
+	}
+
+	meta, err := adminClient.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, fmt.Errorf("unable to get metadata for topic %s: %w", topic, err)
+	}
+
+	var partitions []int
+	for _, t := range meta.Topics {
+		if t.Name != topic {
+			continue
+		}
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+	}
+	if len(partitions) == 0 {
+		return 0, fmt.Errorf("topic %s not found", topic)
+	}
+
+	offsetFetchResp, err := adminClient.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: groupID,
+		Topics:  map[string][]int{topic: partitions},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to fetch committed offsets for group %s: %w", groupID, err)
+	}
+
+	listOffsetsReq := make([]kafka.OffsetRequest, len(partitions))
+	for i, partition := range partitions {
+		listOffsetsReq[i] = kafka.LastOffsetOf(partition)
+	}
+
+	listOffsetsResp, err := adminClient.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{topic: listOffsetsReq},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to list offsets for topic %s: %w", topic, err)
+	}
+
+	committedByPartition := make(map[int]int64, len(partitions))
+	for _, partitionOffset := range offsetFetchResp.Topics[topic] {
+		committedByPartition[partitionOffset.Partition] = partitionOffset.CommittedOffset
+	}
+
+	highWatermarkByPartition := make(map[int]int64, len(partitions))
+	for _, partitionOffset := range listOffsetsResp.Topics[topic] {
+		highWatermarkByPartition[partitionOffset.Partition] = partitionOffset.LastOffset
+	}
+
+	return calculateConsumerLag(highWatermarkByPartition, committedByPartition), nil
+}
+
+// calculateConsumerLag sums the per-partition lag (high watermark minus
+// committed offset) across every partition in highWatermarkByPartition. A
+// committed offset of -1 is the OffsetFetch sentinel for "never committed"
+// on that partition/group and is treated as 0 rather than inflating the
+// lag; a negative lag (shouldn't normally happen, but tolerated for safety)
+// doesn't subtract from the total.
+func calculateConsumerLag(highWatermarkByPartition map[int]int64, committedByPartition map[int]int64) int64 {
+	var totalLag int64
+	for partition, highWatermark := range highWatermarkByPartition {
+		committed := committedByPartition[partition]
+		if committed < 0 {
+			committed = 0
+		}
+		lag := highWatermark - committed
+		if lag > 0 {
+			totalLag += lag
+		}
+	}
+	return totalLag
+}
+
 func getKafkaLogger(level string) (kafka.LoggerFunc, kafka.LoggerFunc) {
 	noop := kafka.LoggerFunc(func(msg string, args ...interface{}) {})
 
@@ -0,0 +1,123 @@
+package kafka_client
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func generateTestCAPEM(t *testing.T) string {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unable to generate test CA key: %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("unable to create test CA certificate: %v", err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestLoadCertPool(t *testing.T) {
+	caPEM := generateTestCAPEM(t)
+
+	t.Run("inline PEM", func(t *testing.T) {
+		pool, err := loadCertPool(caPEM)
+		if err != nil {
+			t.Fatalf("loadCertPool returned error: %v", err)
+		}
+		if pool == nil {
+			t.Fatal("expected a non-nil cert pool")
+		}
+	})
+
+	t.Run("path to PEM file", func(t *testing.T) {
+		caPath := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caPath, []byte(caPEM), 0o600); err != nil {
+			t.Fatalf("unable to write test CA file: %v", err)
+		}
+
+		pool, err := loadCertPool(caPath)
+		if err != nil {
+			t.Fatalf("loadCertPool returned error: %v", err)
+		}
+		if pool == nil {
+			t.Fatal("expected a non-nil cert pool")
+		}
+	})
+
+	t.Run("invalid PEM", func(t *testing.T) {
+		invalidPEM := "-----BEGIN CERTIFICATE-----\nnot a certificate\n-----END CERTIFICATE-----\n"
+		if _, err := loadCertPool(invalidPEM); err == nil {
+			t.Fatal("expected an error for invalid PEM content")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := loadCertPool(filepath.Join(t.TempDir(), "missing.pem")); err == nil {
+			t.Fatal("expected an error for a missing CA file")
+		}
+	})
+}
+
+func TestGetTLSConfig(t *testing.T) {
+	caPEM := generateTestCAPEM(t)
+
+	t.Run("no CA configured", func(t *testing.T) {
+		client := &KafkaClient{SslInsecureSkipVerify: true, SslServerName: "broker.example.com"}
+
+		tlsConfig, err := getTLSConfig(client)
+		if err != nil {
+			t.Fatalf("getTLSConfig returned error: %v", err)
+		}
+		if tlsConfig.RootCAs != nil {
+			t.Fatal("expected RootCAs to be nil when SslCaCert is unset")
+		}
+		if !tlsConfig.InsecureSkipVerify {
+			t.Fatal("expected InsecureSkipVerify to be carried over from the client")
+		}
+		if tlsConfig.ServerName != "broker.example.com" {
+			t.Fatalf("expected ServerName %q, got %q", "broker.example.com", tlsConfig.ServerName)
+		}
+	})
+
+	t.Run("inline CA cert", func(t *testing.T) {
+		client := &KafkaClient{SslCaCert: caPEM}
+
+		tlsConfig, err := getTLSConfig(client)
+		if err != nil {
+			t.Fatalf("getTLSConfig returned error: %v", err)
+		}
+		if tlsConfig.RootCAs == nil {
+			t.Fatal("expected RootCAs to be populated from SslCaCert")
+		}
+	})
+
+	t.Run("invalid CA cert", func(t *testing.T) {
+		client := &KafkaClient{SslCaCert: "not a certificate"}
+
+		if _, err := getTLSConfig(client); err == nil {
+			t.Fatal("expected an error for an invalid SslCaCert")
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package kafka_client
+
+import "testing"
+
+func TestCalculateConsumerLag(t *testing.T) {
+	tests := []struct {
+		name                     string
+		highWatermarkByPartition map[int]int64
+		committedByPartition     map[int]int64
+		want                     int64
+	}{
+		{
+			name:                     "lag across multiple partitions",
+			highWatermarkByPartition: map[int]int64{0: 100, 1: 50},
+			committedByPartition:     map[int]int64{0: 80, 1: 50},
+			want:                     20,
+		},
+		{
+			name:                     "never-committed partition treated as zero, not -1",
+			highWatermarkByPartition: map[int]int64{0: 100},
+			committedByPartition:     map[int]int64{0: -1},
+			want:                     100,
+		},
+		{
+			name:                     "partition missing from committed map treated as zero",
+			highWatermarkByPartition: map[int]int64{0: 100},
+			committedByPartition:     map[int]int64{},
+			want:                     100,
+		},
+		{
+			name:                     "fully caught up partitions contribute no lag",
+			highWatermarkByPartition: map[int]int64{0: 100},
+			committedByPartition:     map[int]int64{0: 100},
+			want:                     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateConsumerLag(tt.highWatermarkByPartition, tt.committedByPartition)
+			if got != tt.want {
+				t.Fatalf("calculateConsumerLag() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package kafka_client
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	DecoderFormatJSON     = "json"
+	DecoderFormatAvro     = "avro"
+	DecoderFormatProtobuf = "protobuf"
+
+	avroMagicByte = byte(0)
+)
+
+// Decoder turns a raw Kafka message into the flat numeric field map Grafana
+// plots. Implementations are free to flatten nested records into dotted
+// field paths (e.g. "metrics.cpu.user") since only top-level numeric keys
+// can be graphed.
+type Decoder interface {
+	Decode(topic string, msg kafka.Message) (map[string]float64, error)
+}
+
+// NewDecoder builds the Decoder configured on Options. An empty
+// DecoderFormat selects the JSONDecoder.
+func NewDecoder(options Options) (Decoder, error) {
+	switch strings.ToLower(options.DecoderFormat) {
+	case "", DecoderFormatJSON:
+		return &JSONDecoder{}, nil
+	case DecoderFormatAvro:
+		var tlsConfig *tls.Config
+		if options.SchemaRegistryTLS {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS13}
+		}
+		registry := NewSchemaRegistryClient(
+			options.SchemaRegistryUrl,
+			options.SchemaRegistryUsername,
+			options.SchemaRegistryPassword,
+			tlsConfig,
+		)
+		return &AvroDecoder{registry: registry}, nil
+	case DecoderFormatProtobuf:
+		messageDescriptor, err := loadProtobufMessageDescriptor(
+			[]byte(options.ProtobufDescriptorSet),
+			options.ProtobufMessageType,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load protobuf descriptor: %w", err)
+		}
+		return &ProtobufDecoder{messageDescriptor: messageDescriptor}, nil
+	default:
+		return nil, fmt.Errorf("unsupported decoder format: %s", options.DecoderFormat)
+	}
+}
+
+// flattenNumericFields walks a decoded record, descending into nested
+// objects and flattening them into dot-separated paths, keeping only the
+// numeric leaves.
+func flattenNumericFields(prefix string, value interface{}, out map[string]float64) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenNumericFields(path, nested, out)
+		}
+	case float64:
+		out[prefix] = v
+	case float32:
+		out[prefix] = float64(v)
+	case int:
+		out[prefix] = float64(v)
+	case int32:
+		out[prefix] = float64(v)
+	case int64:
+		out[prefix] = float64(v)
+	}
+}
+
+// JSONDecoder decodes flat or nested JSON objects: numeric leaves are
+// flattened into dotted field paths and non-numeric fields are skipped.
+type JSONDecoder struct{}
+
+func (d *JSONDecoder) Decode(topic string, msg kafka.Message) (map[string]float64, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(msg.Value, &raw); err != nil {
+		return nil, fmt.Errorf("error unmarshalling json message: %w", err)
+	}
+
+	result := make(map[string]float64)
+	flattenNumericFields("", raw, result)
+
+	return result, nil
+}
+
+// AvroDecoder decodes Confluent-framed Avro messages: a magic byte, a
+// 4-byte big-endian schema ID, then the Avro binary payload.
+type AvroDecoder struct {
+	registry *SchemaRegistryClient
+}
+
+func (d *AvroDecoder) Decode(topic string, msg kafka.Message) (map[string]float64, error) {
+	if len(msg.Value) < 5 {
+		return nil, fmt.Errorf("avro message is too short to contain a schema registry header")
+	}
+	if msg.Value[0] != avroMagicByte {
+		return nil, fmt.Errorf("unexpected avro magic byte: %d", msg.Value[0])
+	}
+
+	schemaID := int(binary.BigEndian.Uint32(msg.Value[1:5]))
+
+	schema, err := d.registry.GetSchema(schemaID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch avro schema %d: %w", schemaID, err)
+	}
+
+	codec, err := goavro.NewCodec(schema)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse avro schema %d: %w", schemaID, err)
+	}
+
+	native, _, err := codec.NativeFromBinary(msg.Value[5:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode avro payload: %w", err)
+	}
+
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("decoded avro payload for schema %d is not a record", schemaID)
+	}
+
+	result := make(map[string]float64)
+	flattenNumericFields("", record, result)
+
+	return result, nil
+}
+
+// ProtobufDecoder decodes messages against a single message type resolved
+// from a user-supplied FileDescriptorSet.
+type ProtobufDecoder struct {
+	messageDescriptor protoreflect.MessageDescriptor
+}
+
+func (d *ProtobufDecoder) Decode(topic string, msg kafka.Message) (map[string]float64, error) {
+	dynMsg := dynamicpb.NewMessage(d.messageDescriptor)
+	if err := proto.Unmarshal(msg.Value, dynMsg); err != nil {
+		return nil, fmt.Errorf("unable to decode protobuf payload: %w", err)
+	}
+
+	result := make(map[string]float64)
+	flattenNumericFields("", protobufMessageToMap(dynMsg), result)
+
+	return result, nil
+}
+
+func protobufMessageToMap(msg protoreflect.Message) map[string]interface{} {
+	out := make(map[string]interface{})
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		out[string(fd.Name())] = protobufFieldValue(fd, v)
+		return true
+	})
+	return out
+}
+
+func protobufFieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	if fd.Kind() == protoreflect.MessageKind || fd.Kind() == protoreflect.GroupKind {
+		return protobufMessageToMap(v.Message())
+	}
+	return v.Interface()
+}
+
+// loadProtobufMessageDescriptor parses a serialized FileDescriptorSet and
+// resolves the fully-qualified message type that incoming payloads decode
+// into.
+func loadProtobufMessageDescriptor(descriptorSet []byte, messageType string) (protoreflect.MessageDescriptor, error) {
+	var fdSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(descriptorSet, &fdSet); err != nil {
+		return nil, fmt.Errorf("unable to parse protobuf FileDescriptorSet: %w", err)
+	}
+
+	files, err := protodesc.NewFiles(&fdSet)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build protobuf file registry: %w", err)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(messageType))
+	if err != nil {
+		return nil, fmt.Errorf("unable to find protobuf message %q: %w", messageType, err)
+	}
+
+	messageDescriptor, ok := descriptor.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a protobuf message type", messageType)
+	}
+
+	return messageDescriptor, nil
+}
+
+// SchemaRegistryClient fetches and caches Avro schemas by ID from a
+// Confluent-compatible Schema Registry.
+type SchemaRegistryClient struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[int]string
+}
+
+func NewSchemaRegistryClient(baseURL, username, password string, tlsConfig *tls.Config) *SchemaRegistryClient {
+	httpClient := &http.Client{Timeout: dialerTimeout}
+	if tlsConfig != nil {
+		httpClient.Transport = &http.Transport{TLSClientConfig: tlsConfig}
+	}
+
+	return &SchemaRegistryClient{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		client:   httpClient,
+		cache:    make(map[int]string),
+	}
+}
+
+type schemaRegistrySchemaResponse struct {
+	Schema string `json:"schema"`
+}
+
+func (r *SchemaRegistryClient) GetSchema(id int) (string, error) {
+	r.mu.Lock()
+	if schema, ok := r.cache[id]; ok {
+		r.mu.Unlock()
+		return schema, nil
+	}
+	r.mu.Unlock()
+
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/schemas/ids/%d", r.baseURL, id), nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to build schema registry request: %w", err)
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("unable to reach schema registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("schema registry returned status %d for schema %d", resp.StatusCode, id)
+	}
+
+	var parsed schemaRegistrySchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("unable to parse schema registry response for schema %d: %w", id, err)
+	}
+
+	r.mu.Lock()
+	r.cache[id] = parsed.Schema
+	r.mu.Unlock()
+
+	return parsed.Schema, nil
+}
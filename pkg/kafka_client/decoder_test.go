@@ -0,0 +1,218 @@
+package kafka_client
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// dynamicMetricMessage builds a dynamicpb message matching the single-field
+// "Metric" descriptor used by TestProtobufDecoder_Decode, with its "value"
+// field set to v.
+func dynamicMetricMessage(t *testing.T, messageDescriptor protoreflect.MessageDescriptor, v float64) *dynamicpb.Message {
+	t.Helper()
+
+	msg := dynamicpb.NewMessage(messageDescriptor)
+	fd := messageDescriptor.Fields().ByName("value")
+	msg.Set(fd, protoreflect.ValueOfFloat64(v))
+	return msg
+}
+
+func TestFlattenNumericFields(t *testing.T) {
+	input := map[string]interface{}{
+		"cpu": float64(0.5),
+		"nested": map[string]interface{}{
+			"memory": float64(1024),
+			"label":  "ignored",
+		},
+		"count": int(3),
+		"label": "also ignored",
+	}
+
+	out := make(map[string]float64)
+	flattenNumericFields("", input, out)
+
+	want := map[string]float64{
+		"cpu":           0.5,
+		"nested.memory": 1024,
+		"count":         3,
+	}
+	if len(out) != len(want) {
+		t.Fatalf("got %d fields, want %d: %v", len(out), len(want), out)
+	}
+	for k, v := range want {
+		if out[k] != v {
+			t.Errorf("field %q = %v, want %v", k, out[k], v)
+		}
+	}
+}
+
+func TestJSONDecoder_Decode(t *testing.T) {
+	decoder := &JSONDecoder{}
+
+	t.Run("flattens nested numeric fields", func(t *testing.T) {
+		msg := kafka.Message{Value: []byte(`{"cpu": 1.5, "mem": {"used": 2048}}`)}
+
+		result, err := decoder.Decode("topic", msg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result["cpu"] != 1.5 || result["mem.used"] != 2048 {
+			t.Fatalf("unexpected result: %v", result)
+		}
+	})
+
+	t.Run("rejects invalid json", func(t *testing.T) {
+		msg := kafka.Message{Value: []byte(`not json`)}
+
+		if _, err := decoder.Decode("topic", msg); err == nil {
+			t.Fatal("expected an error for invalid json")
+		}
+	})
+}
+
+func TestAvroDecoder_Decode(t *testing.T) {
+	const schema = `{"type":"record","name":"Metric","fields":[{"name":"value","type":"double"}]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"schema": schema})
+	}))
+	defer server.Close()
+
+	decoder := &AvroDecoder{registry: NewSchemaRegistryClient(server.URL, "", "", nil)}
+
+	t.Run("decodes a confluent-framed message", func(t *testing.T) {
+		codec, err := goavro.NewCodec(schema)
+		if err != nil {
+			t.Fatalf("unable to build avro codec: %v", err)
+		}
+		native, _, err := codec.NativeFromTextual([]byte(`{"value": 3.25}`))
+		if err != nil {
+			t.Fatalf("unable to build native avro value: %v", err)
+		}
+		payload, err := codec.BinaryFromNative(nil, native)
+		if err != nil {
+			t.Fatalf("unable to encode avro payload: %v", err)
+		}
+
+		value := make([]byte, 5+len(payload))
+		value[0] = avroMagicByte
+		binary.BigEndian.PutUint32(value[1:5], 1)
+		copy(value[5:], payload)
+
+		result, err := decoder.Decode("topic", kafka.Message{Value: value})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result["value"] != 3.25 {
+			t.Fatalf("unexpected result: %v", result)
+		}
+	})
+
+	t.Run("rejects a message too short to hold the schema registry header", func(t *testing.T) {
+		if _, err := decoder.Decode("topic", kafka.Message{Value: []byte{0, 0}}); err == nil {
+			t.Fatal("expected an error for a too-short message")
+		}
+	})
+
+	t.Run("rejects an unexpected magic byte", func(t *testing.T) {
+		value := []byte{1, 0, 0, 0, 1}
+		if _, err := decoder.Decode("topic", kafka.Message{Value: value}); err == nil {
+			t.Fatal("expected an error for an unexpected magic byte")
+		}
+	})
+}
+
+func TestProtobufDecoder_Decode(t *testing.T) {
+	fieldName := "value"
+	fieldNumber := int32(1)
+	fieldType := descriptorpb.FieldDescriptorProto_TYPE_DOUBLE
+	fieldLabel := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL
+	messageName := "Metric"
+	fileName := "metric.proto"
+	syntax := "proto3"
+
+	fdSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:   &fileName,
+				Syntax: &syntax,
+				MessageType: []*descriptorpb.DescriptorProto{
+					{
+						Name: &messageName,
+						Field: []*descriptorpb.FieldDescriptorProto{
+							{
+								Name:     &fieldName,
+								Number:   &fieldNumber,
+								Type:     &fieldType,
+								Label:    &fieldLabel,
+								JsonName: &fieldName,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	descriptorSet, err := proto.Marshal(fdSet)
+	if err != nil {
+		t.Fatalf("unable to marshal test descriptor set: %v", err)
+	}
+
+	messageDescriptor, err := loadProtobufMessageDescriptor(descriptorSet, "Metric")
+	if err != nil {
+		t.Fatalf("unable to load test message descriptor: %v", err)
+	}
+
+	decoder := &ProtobufDecoder{messageDescriptor: messageDescriptor}
+
+	dynMsg := dynamicMetricMessage(t, messageDescriptor, 4.5)
+	payload, err := proto.Marshal(dynMsg)
+	if err != nil {
+		t.Fatalf("unable to marshal test protobuf message: %v", err)
+	}
+
+	result, err := decoder.Decode("topic", kafka.Message{Value: payload})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result["value"] != 4.5 {
+		t.Fatalf("unexpected result: %v", result)
+	}
+}
+
+func TestLoadProtobufMessageDescriptor_UnknownMessage(t *testing.T) {
+	messageName := "Metric"
+	fileName := "metric.proto"
+	syntax := "proto3"
+
+	fdSet := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:        &fileName,
+				Syntax:      &syntax,
+				MessageType: []*descriptorpb.DescriptorProto{{Name: &messageName}},
+			},
+		},
+	}
+
+	descriptorSet, err := proto.Marshal(fdSet)
+	if err != nil {
+		t.Fatalf("unable to marshal test descriptor set: %v", err)
+	}
+
+	if _, err := loadProtobufMessageDescriptor(descriptorSet, "DoesNotExist"); err == nil {
+		t.Fatal("expected an error for an unknown message type")
+	}
+}
@@ -0,0 +1,90 @@
+package kafka_client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	defaultMaxRetries     = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
+)
+
+// retryConfig resolves the client's MaxRetries/InitialBackoffMs/MaxBackoffMs
+// options, falling back to sane defaults for anything left unset.
+func (client *KafkaClient) retryConfig() (maxRetries int, initialBackoff time.Duration, maxBackoff time.Duration) {
+	maxRetries = int(client.MaxRetries)
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	initialBackoff = time.Duration(client.InitialBackoffMs) * time.Millisecond
+	if initialBackoff <= 0 {
+		initialBackoff = defaultInitialBackoff
+	}
+
+	maxBackoff = time.Duration(client.MaxBackoffMs) * time.Millisecond
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	return maxRetries, initialBackoff, maxBackoff
+}
+
+// isRetryableError classifies an error from dialing or reading as a
+// transient condition worth retrying: EOF from a dropped connection, an
+// in-progress consumer group rebalance, or a network-level error (timeouts,
+// refused connections, DNS blips).
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+	if errors.Is(err, kafka.RebalanceInProgress) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// retryWithBackoff runs fn until it succeeds, returns a non-retryable
+// error, or maxRetries additional attempts are exhausted, sleeping for an
+// exponentially increasing backoff (capped at maxBackoff) between
+// attempts. It gives up early if ctx is canceled while waiting.
+func retryWithBackoff(ctx context.Context, maxRetries int, initialBackoff time.Duration, maxBackoff time.Duration, fn func() error) error {
+	backoff := initialBackoff
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+
+		if attempt == maxRetries || !isRetryableError(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return err
+}
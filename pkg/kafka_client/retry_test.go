@@ -0,0 +1,122 @@
+package kafka_client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "nil error is not retryable",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "EOF is retryable",
+			err:  io.EOF,
+			want: true,
+		},
+		{
+			name: "wrapped EOF is retryable",
+			err:  fmt.Errorf("reading message: %w", io.EOF),
+			want: true,
+		},
+		{
+			name: "rebalance in progress is retryable",
+			err:  kafka.RebalanceInProgress,
+			want: true,
+		},
+		{
+			name: "net.Error is retryable",
+			err:  &net.OpError{Op: "dial", Err: errors.New("connection refused")},
+			want: true,
+		},
+		{
+			name: "unrelated error is not retryable",
+			err:  errors.New("malformed message"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableError(tt.err); got != tt.want {
+				t.Fatalf("isRetryableError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	t.Run("succeeds without retrying", func(t *testing.T) {
+		calls := 0
+		err := retryWithBackoff(context.Background(), 3, time.Millisecond, time.Millisecond, func() error {
+			calls++
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("retries retryable errors until success", func(t *testing.T) {
+		calls := 0
+		err := retryWithBackoff(context.Background(), 3, time.Millisecond, time.Millisecond, func() error {
+			calls++
+			if calls < 3 {
+				return io.EOF
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if calls != 3 {
+			t.Fatalf("expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("returns immediately on a non-retryable error", func(t *testing.T) {
+		calls := 0
+		wantErr := errors.New("permanent failure")
+		err := retryWithBackoff(context.Background(), 3, time.Millisecond, time.Millisecond, func() error {
+			calls++
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("expected %v, got %v", wantErr, err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("gives up after maxRetries additional attempts", func(t *testing.T) {
+		calls := 0
+		err := retryWithBackoff(context.Background(), 2, time.Millisecond, time.Millisecond, func() error {
+			calls++
+			return io.EOF
+		})
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("expected io.EOF, got %v", err)
+		}
+		if calls != 3 {
+			t.Fatalf("expected 3 calls (1 initial + 2 retries), got %d", calls)
+		}
+	})
+}
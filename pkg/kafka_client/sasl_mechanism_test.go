@@ -0,0 +1,89 @@
+package kafka_client
+
+import "testing"
+
+func TestGetSASLMechanism(t *testing.T) {
+	t.Run("no mechanism configured", func(t *testing.T) {
+		mechanism, err := getSASLMechanism(&KafkaClient{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mechanism != nil {
+			t.Fatalf("expected a nil mechanism, got %v", mechanism)
+		}
+	})
+
+	t.Run("plain", func(t *testing.T) {
+		client := &KafkaClient{SaslMechanisms: "PLAIN", SaslUsername: "user", SaslPassword: "pass"}
+
+		mechanism, err := getSASLMechanism(client)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mechanism.Name() != "PLAIN" {
+			t.Fatalf("unexpected mechanism name: %q", mechanism.Name())
+		}
+	})
+
+	t.Run("scram-sha-256", func(t *testing.T) {
+		client := &KafkaClient{SaslMechanisms: "SCRAM-SHA-256", SaslUsername: "user", SaslPassword: "pass"}
+
+		if _, err := getSASLMechanism(client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("scram-sha-512", func(t *testing.T) {
+		client := &KafkaClient{SaslMechanisms: "SCRAM-SHA-512", SaslUsername: "user", SaslPassword: "pass"}
+
+		if _, err := getSASLMechanism(client); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("unsupported mechanism", func(t *testing.T) {
+		client := &KafkaClient{SaslMechanisms: "UNSUPPORTED"}
+
+		if _, err := getSASLMechanism(client); err == nil {
+			t.Fatal("expected an error for an unsupported mechanism")
+		}
+	})
+}
+
+func TestGetAWSMSKIAMMechanism(t *testing.T) {
+	t.Run("static credentials", func(t *testing.T) {
+		client := &KafkaClient{
+			AwsRegion:          "us-east-1",
+			AwsAccessKeyId:     "AKIAEXAMPLE",
+			AwsSecretAccessKey: "secret",
+		}
+
+		mechanism, err := getAWSMSKIAMMechanism(client)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mechanism == nil {
+			t.Fatal("expected a non-nil sasl.Mechanism")
+		}
+		if mechanism.Name() != "AWS_MSK_IAM" {
+			t.Fatalf("unexpected mechanism name: %q", mechanism.Name())
+		}
+	})
+
+	t.Run("assumes a role when AwsRoleArn is set", func(t *testing.T) {
+		client := &KafkaClient{
+			AwsRegion:          "us-east-1",
+			AwsAccessKeyId:     "AKIAEXAMPLE",
+			AwsSecretAccessKey: "secret",
+			AwsRoleArn:         "arn:aws:iam::123456789012:role/kafka-reader",
+		}
+
+		mechanism, err := getAWSMSKIAMMechanism(client)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mechanism == nil {
+			t.Fatal("expected a non-nil sasl.Mechanism")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package kafka_client
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/sizovilya/grafana-kafka-datasource/pkg/kafka_client"
+
+// ClientOption configures optional telemetry on a KafkaClient. Without any
+// options, tracing uses the global TracerProvider and metrics are
+// registered against a private, process-wide registry shared by every
+// KafkaClient, so the datasource's per-query/per-health-check construction
+// pattern reuses collectors instead of colliding; that registry is not
+// exposed for scraping.
+type ClientOption func(*KafkaClient)
+
+// WithTracerProvider makes the client create its spans through tp instead
+// of the global OpenTelemetry TracerProvider.
+func WithTracerProvider(tp trace.TracerProvider) ClientOption {
+	return func(client *KafkaClient) {
+		client.tracer = tp.Tracer(instrumentationName)
+	}
+}
+
+// WithMetricsRegisterer registers the client's Prometheus metrics with reg
+// instead of the private, process-wide default registry.
+func WithMetricsRegisterer(reg prometheus.Registerer) ClientOption {
+	return func(client *KafkaClient) {
+		client.metrics = newKafkaMetrics(reg)
+	}
+}
+
+// kafkaMetrics holds the Prometheus collectors shared by every instrumented
+// KafkaClient operation.
+type kafkaMetrics struct {
+	messagesConsumed   *prometheus.CounterVec
+	decodeErrors       *prometheus.CounterVec
+	dialDuration       prometheus.Histogram
+	healthCheckLatency prometheus.Histogram
+}
+
+// defaultRegistry is shared by every KafkaClient constructed without an
+// explicit WithMetricsRegisterer, so the per-query/per-health-check clients
+// the datasource creates reuse the same collectors instead of registering
+// new ones. It is unexported and nothing in this package gathers or serves
+// it; the metrics can only be scraped through a registerer passed with
+// WithMetricsRegisterer.
+var (
+	defaultRegistryOnce sync.Once
+	defaultRegistry     *prometheus.Registry
+)
+
+func defaultRegisterer() prometheus.Registerer {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = prometheus.NewRegistry()
+	})
+	return defaultRegistry
+}
+
+func newKafkaMetrics(reg prometheus.Registerer) *kafkaMetrics {
+	if reg == nil {
+		reg = defaultRegisterer()
+	}
+
+	return &kafkaMetrics{
+		messagesConsumed: registerCounterVec(reg, prometheus.CounterOpts{
+			Name: "kafka_datasource_messages_consumed_total",
+			Help: "Number of Kafka messages consumed, labeled by topic and partition.",
+		}, []string{"topic", "partition"}),
+		decodeErrors: registerCounterVec(reg, prometheus.CounterOpts{
+			Name: "kafka_datasource_decode_errors_total",
+			Help: "Number of Kafka message decode errors, labeled by topic.",
+		}, []string{"topic"}),
+		dialDuration: registerHistogram(reg, prometheus.HistogramOpts{
+			Name: "kafka_datasource_dial_duration_seconds",
+			Help: "Duration of dials to the Kafka broker.",
+		}),
+		healthCheckLatency: registerHistogram(reg, prometheus.HistogramOpts{
+			Name: "kafka_datasource_healthcheck_duration_seconds",
+			Help: "Duration of Kafka health checks.",
+		}),
+	}
+}
+
+// registerCounterVec registers collector with reg, reusing the already
+// registered collector of the same name instead of panicking when reg has
+// seen it before (e.g. a shared registry passed to multiple KafkaClients).
+func registerCounterVec(reg prometheus.Registerer, opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	collector := prometheus.NewCounterVec(opts, labelNames)
+
+	if err := reg.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector.(*prometheus.CounterVec)
+		}
+		panic(err)
+	}
+
+	return collector
+}
+
+// registerHistogram mirrors registerCounterVec for prometheus.Histogram
+// collectors.
+func registerHistogram(reg prometheus.Registerer, opts prometheus.HistogramOpts) prometheus.Histogram {
+	collector := prometheus.NewHistogram(opts)
+
+	if err := reg.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector.(prometheus.Histogram)
+		}
+		panic(err)
+	}
+
+	return collector
+}
+
+// tracerOrDefault returns the client's configured tracer, falling back to
+// the global TracerProvider when none was injected via WithTracerProvider.
+func (client *KafkaClient) tracerOrDefault() trace.Tracer {
+	if client.tracer != nil {
+		return client.tracer
+	}
+	return otel.Tracer(instrumentationName)
+}
+
+func (client *KafkaClient) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return client.tracerOrDefault().Start(ctx, name)
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
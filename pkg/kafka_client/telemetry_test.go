@@ -0,0 +1,33 @@
+package kafka_client
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestNewKafkaMetrics_SharedRegistererDoesNotPanic(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("newKafkaMetrics panicked on a shared registerer: %v", r)
+		}
+	}()
+
+	first := newKafkaMetrics(reg)
+	second := newKafkaMetrics(reg)
+
+	if first.messagesConsumed != second.messagesConsumed {
+		t.Fatal("expected repeat registration to reuse the existing collector")
+	}
+}
+
+func TestNewKafkaMetrics_NilRegistererSharesDefaultRegistry(t *testing.T) {
+	first := newKafkaMetrics(nil)
+	second := newKafkaMetrics(nil)
+
+	if first.messagesConsumed != second.messagesConsumed {
+		t.Fatal("expected default-registerer clients to share the same collector")
+	}
+}